@@ -0,0 +1,60 @@
+package v1
+
+// SecretStore represents a secure external location for storing secrets,
+// which can be referenced as part of a workload container environment.
+// It mirrors the upstream External Secrets Operator SecretStore shape so
+// existing provider configuration can be reused as-is.
+type SecretStore struct {
+	// Provider configures the secret store provider backing this SecretStore.
+	Provider *ProviderSpec `json:"provider" yaml:"provider"`
+}
+
+// ProviderSpec contains the configuration for a single secret store
+// provider. Exactly one of its fields should be set.
+type ProviderSpec struct {
+	// AWS configures this secret store to use AWS Secrets Manager.
+	AWS *AWSProvider `json:"aws,omitempty" yaml:"aws,omitempty"`
+	// OnPremises configures this secret store to use an on-premises,
+	// user-registered provider, identified by Name.
+	OnPremises *OnPremisesProvider `json:"onPremises,omitempty" yaml:"onPremises,omitempty"`
+}
+
+// AWSProvider configures a SecretStore to use AWS Secrets Manager.
+type AWSProvider struct {
+	// Region is the AWS region the secrets live in.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// Profile is the named AWS credentials profile to authenticate with. If
+	// empty, the default credential chain is used.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+}
+
+// OnPremisesProvider configures a SecretStore to use a provider registered
+// outside the built-in set, identified by Name.
+type OnPremisesProvider struct {
+	// Name is the registered name of the on-premises provider, e.g. "vault".
+	Name string `json:"name" yaml:"name"`
+}
+
+// ExternalSecretRef points at a single secret (or a single property within
+// one) held by a SecretStore.
+type ExternalSecretRef struct {
+	// StoreID selects which of a Registry's named SecretStore instances this
+	// reference resolves against. If empty, the caller's default store is
+	// used.
+	StoreID string `json:"storeID,omitempty" yaml:"storeID,omitempty"`
+	// Name is the name of the secret in the external store.
+	Name string `json:"name" yaml:"name"`
+	// Property is the key to read within the secret's JSON payload. If
+	// empty, the whole secret value is used.
+	Property string `json:"property,omitempty" yaml:"property,omitempty"`
+	// Version is the secret version to read, in whatever form the provider
+	// accepts (e.g. an AWS Secrets Manager version stage, or "uuid/<id>" for
+	// a specific version id). If empty, the provider's current version is
+	// used.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// VersionStage is the AWS Secrets Manager version stage (e.g.
+	// "AWSCURRENT", "AWSPREVIOUS") that SetSecret tags a new version with,
+	// and diffs/merges the existing value against. If empty, SetSecret uses
+	// Version instead, and "AWSCURRENT" if that is empty too.
+	VersionStage string `json:"versionStage,omitempty" yaml:"versionStage,omitempty"`
+}
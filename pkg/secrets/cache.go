@@ -0,0 +1,223 @@
+package secrets
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// CacheOptions configures NewCachingStore.
+type CacheOptions struct {
+	// DefaultTTL is how long a successfully fetched value stays cached.
+	// Zero disables positive caching.
+	DefaultTTL time.Duration
+	// MaxEntries bounds the number of cached entries; the least recently
+	// used one is evicted once it would be exceeded. Zero means unbounded.
+	MaxEntries int
+	// NegativeTTL is how long a "secret not found" result stays cached, to
+	// stop a misconfigured ref from hammering the backend in a hot loop.
+	// Zero disables negative caching.
+	NegativeTTL time.Duration
+}
+
+// CacheStats exposes a caching store's hit/miss counters so callers can wire
+// them into Kusion's metrics.
+type CacheStats interface {
+	Hits() int64
+	Misses() int64
+}
+
+// NewCachingStore wraps inner with an in-memory cache of GetSecret results,
+// keyed by (Name, Version, Property), bounded by opts.MaxEntries and expired
+// per opts.DefaultTTL / opts.NegativeTTL. Concurrent gets for the same key are
+// collapsed into a single call to inner via singleflight. SetSecret
+// invalidates every cached entry for its Name before delegating to inner.
+func NewCachingStore(inner SecretStore, opts CacheOptions) SecretStore {
+	return &cachingStore{
+		inner:   inner,
+		opts:    opts,
+		entries: map[cacheEntryKey]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+var (
+	_ SecretStore = &cachingStore{}
+	_ CacheStats  = &cachingStore{}
+)
+
+type cacheEntryKey struct {
+	name, version, property string
+}
+
+// cacheEntry holds a cached GetSecret outcome. notFound distinguishes a
+// negatively-cached "secret does not exist" from a cached empty value.
+type cacheEntry struct {
+	key      cacheEntryKey
+	value    []byte
+	notFound bool
+	expires  time.Time
+}
+
+type cachingStore struct {
+	inner SecretStore
+	opts  CacheOptions
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[cacheEntryKey]*list.Element
+	order   *list.List // front = most recently used
+	hits    int64
+	misses  int64
+}
+
+// GetSecret returns the cached value for ref if present and unexpired,
+// otherwise fetches it from inner (collapsing concurrent callers for the same
+// key into one fetch) and caches the outcome.
+func (c *cachingStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	key := cacheEntryKey{name: ref.Name, version: ref.Version, property: ref.Property}
+
+	if value, found, ok := c.lookup(key); ok {
+		c.recordHit()
+		if !found {
+			return nil, nil
+		}
+		return value, nil
+	}
+	c.recordMiss()
+
+	groupKey := fmt.Sprintf("%s\x00%s\x00%s", key.name, key.version, key.property)
+	result, err, _ := c.group.Do(groupKey, func() (any, error) {
+		value, err := c.inner.GetSecret(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, value)
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.([]byte), nil
+}
+
+// SetSecret writes through to inner and, on success, drops every cached entry
+// for ref.Name so a subsequent GetSecret observes the new value.
+func (c *cachingStore) SetSecret(ctx context.Context, ref v1.ExternalSecretRef, secretValue []byte) error {
+	if err := c.inner.SetSecret(ctx, ref, secretValue); err != nil {
+		return err
+	}
+	c.invalidate(ref.Name)
+	return nil
+}
+
+// Hits returns the number of GetSecret calls served from the cache.
+func (c *cachingStore) Hits() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// Misses returns the number of GetSecret calls that required a call to inner.
+func (c *cachingStore) Misses() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.misses
+}
+
+// lookup returns the cached value for key. ok reports whether a live entry
+// existed at all; found reports whether that entry represents a resolved
+// secret as opposed to a negatively-cached miss.
+func (c *cachingStore) lookup(key cacheEntryKey) (value []byte, found bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, exists := c.entries[key]
+	if !exists {
+		return nil, false, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.removeLocked(el)
+		return nil, false, false
+	}
+	c.order.MoveToFront(el)
+	return entry.value, !entry.notFound, true
+}
+
+// store caches value for key according to opts, evicting the least recently
+// used entry if that would push the cache past MaxEntries. A zero TTL for the
+// outcome being stored (positive or negative) disables caching it.
+func (c *cachingStore) store(key cacheEntryKey, value []byte) {
+	notFound := value == nil
+	ttl := c.opts.DefaultTTL
+	if notFound {
+		ttl = c.opts.NegativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, value: value, notFound: notFound, expires: time.Now().Add(ttl)}
+	if el, exists := c.entries[key]; exists {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.evictIfNeededLocked()
+}
+
+func (c *cachingStore) evictIfNeededLocked() {
+	if c.opts.MaxEntries <= 0 {
+		return
+	}
+	for len(c.entries) > c.opts.MaxEntries {
+		oldest := c.order.Back()
+		if oldest == nil {
+			return
+		}
+		c.removeLocked(oldest)
+	}
+}
+
+func (c *cachingStore) invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.entries {
+		if key.name == name {
+			c.removeLocked(el)
+		}
+	}
+}
+
+func (c *cachingStore) removeLocked(el *list.Element) {
+	entry := el.Value.(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.order.Remove(el)
+}
+
+func (c *cachingStore) recordHit() {
+	c.mu.Lock()
+	c.hits++
+	c.mu.Unlock()
+}
+
+func (c *cachingStore) recordMiss() {
+	c.mu.Lock()
+	c.misses++
+	c.mu.Unlock()
+}
@@ -0,0 +1,188 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// countingBackend records how many times GetSecret/SetSecret were called and
+// serves canned values keyed by name.
+type countingBackend struct {
+	mu       sync.Mutex
+	values   map[string][]byte
+	getCalls int
+	setCalls int
+}
+
+func (b *countingBackend) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.getCalls++
+	return b.values[ref.Name], nil
+}
+
+func (b *countingBackend) SetSecret(_ context.Context, ref v1.ExternalSecretRef, value []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setCalls++
+	b.values[ref.Name] = value
+	return nil
+}
+
+func (b *countingBackend) calls() (get, set int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.getCalls, b.setCalls
+}
+
+func TestCachingStoreServesHitsFromCache(t *testing.T) {
+	backend := &countingBackend{values: map[string][]byte{"db": []byte("secret")}}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		value, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "db"})
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	}
+
+	get, _ := backend.calls()
+	assert.Equal(t, 1, get, "only the first GetSecret should reach the backend")
+
+	stats := store.(CacheStats)
+	assert.Equal(t, int64(2), stats.Hits())
+	assert.Equal(t, int64(1), stats.Misses())
+}
+
+func TestCachingStoreExpiresEntriesAfterTTL(t *testing.T) {
+	backend := &countingBackend{values: map[string][]byte{"db": []byte("secret")}}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: 20 * time.Millisecond})
+
+	_, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "db"})
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "db"})
+	require.NoError(t, err)
+
+	get, _ := backend.calls()
+	assert.Equal(t, 2, get, "an expired entry should be re-fetched")
+}
+
+func TestCachingStoreCachesNotFoundWithNegativeTTL(t *testing.T) {
+	backend := &countingBackend{values: map[string][]byte{}}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: time.Minute, NegativeTTL: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		value, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "missing"})
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	}
+
+	get, _ := backend.calls()
+	assert.Equal(t, 1, get, "a not-found result should be negatively cached")
+}
+
+func TestCachingStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	backend := &countingBackend{values: map[string][]byte{
+		"a": []byte("va"), "b": []byte("vb"), "c": []byte("vc"),
+	}}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: time.Minute, MaxEntries: 2})
+
+	ctx := context.Background()
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "a"})
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "b"})
+	// Touch "a" so "b" becomes the least recently used entry.
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "a"})
+	// Adding "c" should evict "b", not "a".
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "c"})
+
+	getBefore, _ := backend.calls()
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "a"})
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "c"})
+	getAfterHits, _ := backend.calls()
+	assert.Equal(t, getBefore, getAfterHits, "a and c should still be cached")
+
+	_, _ = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "b"})
+	getAfterMiss, _ := backend.calls()
+	assert.Equal(t, getAfterHits+1, getAfterMiss, "b should have been evicted and re-fetched")
+}
+
+func TestCachingStoreInvalidatesOnSetSecret(t *testing.T) {
+	backend := &countingBackend{values: map[string][]byte{"db": []byte("old")}}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: time.Minute})
+
+	ctx := context.Background()
+	value, err := store.GetSecret(ctx, v1.ExternalSecretRef{Name: "db"})
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(value))
+
+	require.NoError(t, store.SetSecret(ctx, v1.ExternalSecretRef{Name: "db"}, []byte("new")))
+
+	value, err = store.GetSecret(ctx, v1.ExternalSecretRef{Name: "db"})
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(value))
+
+	get, set := backend.calls()
+	assert.Equal(t, 2, get)
+	assert.Equal(t, 1, set)
+}
+
+// blockingBackend blocks GetSecret until release is closed, so tests can
+// force concurrent callers to race on the same key.
+type blockingBackend struct {
+	mu      sync.Mutex
+	calls   int
+	value   []byte
+	release chan struct{}
+}
+
+func (b *blockingBackend) GetSecret(_ context.Context, _ v1.ExternalSecretRef) ([]byte, error) {
+	b.mu.Lock()
+	b.calls++
+	b.mu.Unlock()
+	<-b.release
+	return b.value, nil
+}
+
+func (b *blockingBackend) SetSecret(_ context.Context, _ v1.ExternalSecretRef, _ []byte) error {
+	return nil
+}
+
+func TestCachingStoreCollapsesRacingGetsWithSingleflight(t *testing.T) {
+	backend := &blockingBackend{value: []byte("shared"), release: make(chan struct{})}
+	store := NewCachingStore(backend, CacheOptions{DefaultTTL: time.Minute})
+
+	const n = 20
+	var wg sync.WaitGroup
+	results := make([][]byte, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "shared"})
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond) // let every goroutine pile onto the same backend call
+	close(backend.release)
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "shared", string(results[i]))
+	}
+
+	backend.mu.Lock()
+	calls := backend.calls
+	backend.mu.Unlock()
+	assert.Equal(t, 1, calls, "racing gets for the same key should collapse into one backend call")
+}
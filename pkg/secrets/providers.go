@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// SecretStore is the interface a secret provider must implement to let Kusion
+// read and write secrets through an ExternalSecretRef.
+type SecretStore interface {
+	// GetSecret retrieves the value referenced by secretRef.
+	GetSecret(ctx context.Context, secretRef v1.ExternalSecretRef) ([]byte, error)
+	// SetSecret creates or updates the value referenced by secretRef.
+	SetSecret(ctx context.Context, secretRef v1.ExternalSecretRef, secretValue []byte) error
+}
+
+// SecretStoreProvider constructs a SecretStore from a SecretStore spec.
+type SecretStoreProvider interface {
+	NewSecretStore(spec *v1.SecretStore) (SecretStore, error)
+}
+
+// ConfigurableProvider is implemented by providers that can additionally be
+// instantiated from a raw per-instance config block, as used by
+// NewRegistryFromConfig. Providers that only support the legacy, package-global
+// Register path do not need to implement it.
+type ConfigurableProvider interface {
+	SecretStoreProvider
+	// DecodeSpec unmarshals a provider-specific config block (JSON or, once
+	// converted, YAML) into the *v1.SecretStore spec NewSecretStore expects.
+	DecodeSpec(raw []byte) (*v1.SecretStore, error)
+}
+
+// providers holds the process-wide provider types registered via Register,
+// keyed by the name derived from each provider's spec (e.g. "aws", or an
+// on-premises provider's own Name).
+var providers = map[string]SecretStoreProvider{}
+
+// Register registers provider under the name derived from spec. It panics if
+// spec does not identify a provider, since Register is only ever called from
+// package init(), where a mistake should fail loudly and immediately.
+func Register(provider SecretStoreProvider, spec *v1.ProviderSpec) {
+	name, err := providerName(spec)
+	if err != nil {
+		panic(err)
+	}
+	providers[name] = provider
+}
+
+// GetProviderByName returns the provider type registered under name, if any.
+func GetProviderByName(name string) (SecretStoreProvider, bool) {
+	provider, ok := providers[name]
+	return provider, ok
+}
+
+// providerName derives the registry key for spec: "aws" for an AWS Secrets
+// Manager spec, or the user-chosen Name for an on-premises provider.
+func providerName(spec *v1.ProviderSpec) (string, error) {
+	switch {
+	case spec == nil:
+		return "", fmt.Errorf("invalid provider spec. spec is nil")
+	case spec.AWS != nil:
+		return "aws", nil
+	case spec.OnPremises != nil:
+		return spec.OnPremises.Name, nil
+	default:
+		return "", fmt.Errorf("invalid provider spec. no known provider set")
+	}
+}
+
+// ProviderConfig describes one named secret store instance as loaded from a
+// declarative JSON/YAML source: an id chosen by the caller (e.g. "prod-ssm"),
+// the registered provider Type backing it (e.g. "aws"), and that provider's
+// own config block, in whatever shape its DecodeSpec expects.
+type ProviderConfig struct {
+	ID     string          `json:"id" yaml:"id"`
+	Type   string          `json:"type" yaml:"type"`
+	Config json.RawMessage `json:"config" yaml:"config"`
+}
+
+// Registry resolves named SecretStore instances. Unlike the package-global
+// providers map, a Registry can hold several instances of the same provider
+// type (e.g. two AWS Secrets Manager stores in different regions).
+type Registry struct {
+	mu     sync.RWMutex
+	stores map[string]SecretStore
+}
+
+// NewRegistryFromConfig builds a Registry with one SecretStore per entry in
+// configs, constructed via the corresponding provider's DecodeSpec.
+func NewRegistryFromConfig(configs []ProviderConfig) (*Registry, error) {
+	reg := &Registry{stores: map[string]SecretStore{}}
+	for _, cfg := range configs {
+		if _, exists := reg.stores[cfg.ID]; exists {
+			return nil, fmt.Errorf("duplicate secret store id %q", cfg.ID)
+		}
+		provider, ok := GetProviderByName(cfg.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown secret store provider type %q for id %q", cfg.Type, cfg.ID)
+		}
+		configurable, ok := provider.(ConfigurableProvider)
+		if !ok {
+			return nil, fmt.Errorf("secret store provider %q does not support config-driven instantiation", cfg.Type)
+		}
+		spec, err := configurable.DecodeSpec(cfg.Config)
+		if err != nil {
+			return nil, fmt.Errorf("decode config for secret store %q: %w", cfg.ID, err)
+		}
+		store, err := configurable.NewSecretStore(spec)
+		if err != nil {
+			return nil, fmt.Errorf("construct secret store %q: %w", cfg.ID, err)
+		}
+		reg.stores[cfg.ID] = store
+	}
+	return reg, nil
+}
+
+// NewDefaultRegistry returns an empty Registry whose stores are populated
+// lazily by Resolve, the compatibility path for callers that still resolve a
+// SecretStore from a single *v1.SecretStore spec rather than a StoreID.
+func NewDefaultRegistry() *Registry {
+	return &Registry{stores: map[string]SecretStore{}}
+}
+
+// Get returns the store registered under id, if any.
+func (r *Registry) Get(id string) (SecretStore, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	store, ok := r.stores[id]
+	return store, ok
+}
+
+// GetForRef returns the store that ref.StoreID selects, if any. It lets a
+// caller that only has an ExternalSecretRef in hand (e.g. the inline
+// ${secret-ref://...} resolver) look up the right named instance without
+// reaching into the Registry's id scheme itself.
+func (r *Registry) GetForRef(ref v1.ExternalSecretRef) (SecretStore, bool) {
+	return r.Get(ref.StoreID)
+}
+
+// Resolve constructs (or reuses) the SecretStore for spec's provider type and
+// caches it in the registry under that type's name. It keeps callers that have
+// not adopted ExternalSecretRef.StoreID working unmodified.
+func (r *Registry) Resolve(spec *v1.SecretStore) (SecretStore, error) {
+	name, err := providerName(spec.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if store, ok := r.Get(name); ok {
+		return store, nil
+	}
+
+	provider, ok := GetProviderByName(name)
+	if !ok {
+		return nil, fmt.Errorf("no secret store provider registered for %q", name)
+	}
+	store, err := provider.NewSecretStore(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.stores[name] = store
+	r.mu.Unlock()
+	return store, nil
+}
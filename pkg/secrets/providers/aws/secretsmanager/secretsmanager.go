@@ -1,14 +1,18 @@
 package secretsmanager
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/secrets"
@@ -19,7 +23,10 @@ const (
 	errMissingProviderSpec   = "store spec is missing provider"
 	errMissingAWSProvider    = "invalid provider spec. Missing AWS field in store provider spec"
 	errFailedToCreateSession = "failed to create usable AWS session: %w"
-	errMethodNotImplemented  = "method not implemented. secret provider: %s, method: %s"
+
+	// awsCurrentStage is the version stage AWS Secrets Manager points at a
+	// secret's latest value unless it is moved elsewhere explicitly.
+	awsCurrentStage = "AWSCURRENT"
 )
 
 // DefaultSecretStoreProvider should implement the secrets.SecretStoreProvider interface
@@ -50,6 +57,37 @@ func (p *DefaultSecretStoreProvider) NewSecretStore(spec *v1.SecretStore) (secre
 	}, nil
 }
 
+// DefaultSecretStoreProvider implements secrets.ConfigurableProvider so a
+// secrets.Registry can construct several AWS Secrets Manager stores, each
+// pointed at a different region/profile, from a declarative config.
+var _ secrets.ConfigurableProvider = &DefaultSecretStoreProvider{}
+
+// awsProviderConfig is the per-instance config block for an "aws" entry in a
+// secrets.ProviderConfig, e.g.:
+//
+//	{"region": "us-west-2", "profile": "prod"}
+type awsProviderConfig struct {
+	Region  string `json:"region" yaml:"region"`
+	Profile string `json:"profile" yaml:"profile"`
+}
+
+// DecodeSpec unmarshals raw as an awsProviderConfig and wraps it in the
+// v1.SecretStore spec NewSecretStore expects.
+func (p *DefaultSecretStoreProvider) DecodeSpec(raw []byte) (*v1.SecretStore, error) {
+	var cfg awsProviderConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("decode aws provider config: %w", err)
+	}
+	return &v1.SecretStore{
+		Provider: &v1.ProviderSpec{
+			AWS: &v1.AWSProvider{
+				Region:  cfg.Region,
+				Profile: cfg.Profile,
+			},
+		},
+	}, nil
+}
+
 type smSecretStore struct {
 	client Client
 }
@@ -81,14 +119,144 @@ func (s *smSecretStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef)
 	return []byte(val.String()), nil
 }
 
-// SetSecret sets ref secret value to AWS Secrets Manager.
+// SetSecret creates or updates ref secret value in AWS Secrets Manager. When the
+// secret does not exist yet, it is created; otherwise a new version is added via
+// PutSecretValue and tagged with the requested version stage (AWSCURRENT by
+// default). If ref.Property is set, the existing secret's JSON payload is read
+// back and only that single property is merged in, leaving unrelated fields
+// untouched. A value that already matches the targeted stage is a no-op, so
+// SetSecret can be used to reconcile desired state idempotently.
 func (s *smSecretStore) SetSecret(ctx context.Context, ref v1.ExternalSecretRef, secretValue []byte) error {
-	return fmt.Errorf(errMethodNotImplemented, "AWS Secret Manager", "SetSecret")
+	existing, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &ref.Name})
+	var nf *types.ResourceNotFoundException
+	notFound := errors.As(err, &nf)
+	if err != nil && !notFound {
+		return err
+	}
+	if notFound {
+		payload := secretValue
+		if ref.Property != "" {
+			merged, mergeErr := sjson.SetBytes(nil, ref.Property, string(secretValue))
+			if mergeErr != nil {
+				return fmt.Errorf("failed to set property %s on secret %s: %w", ref.Property, ref.Name, mergeErr)
+			}
+			payload = merged
+		}
+		return s.createSecret(ctx, ref.Name, payload)
+	}
+
+	// Diff and merge against the stage ref actually targets rather than
+	// always AWSCURRENT, so a stage-move whose payload already matches that
+	// stage is a no-op, and a property merge is based on that stage's own
+	// JSON rather than AWSCURRENT's.
+	target := existing
+	stage := targetVersionStage(ref)
+	if stage != awsCurrentStage {
+		var stageErr error
+		target, stageErr = s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &ref.Name, VersionStage: &stage})
+		var stageNF *types.ResourceNotFoundException
+		switch {
+		case errors.As(stageErr, &stageNF):
+			target = nil
+		case stageErr != nil:
+			return stageErr
+		}
+	}
+
+	payload := secretValue
+	if ref.Property != "" {
+		var current string
+		if target != nil {
+			current = currentSecretString(target)
+		}
+		merged, mergeErr := sjson.SetBytes([]byte(current), ref.Property, string(secretValue))
+		if mergeErr != nil {
+			return fmt.Errorf("failed to set property %s on secret %s: %w", ref.Property, ref.Name, mergeErr)
+		}
+		payload = merged
+	}
+
+	if target != nil && secretValueEqual(target, payload) {
+		// Desired value is already in place; avoid an unnecessary API call.
+		return nil
+	}
+	return s.putSecretValue(ctx, ref, payload)
+}
+
+// createSecret creates a brand-new secret with payload as its initial version.
+func (s *smSecretStore) createSecret(ctx context.Context, name string, payload []byte) error {
+	createSecretInput := &secretsmanager.CreateSecretInput{Name: &name}
+	setPayload(payload, &createSecretInput.SecretString, &createSecretInput.SecretBinary)
+	_, err := s.client.CreateSecret(ctx, createSecretInput)
+	return err
+}
+
+// targetVersionStage returns the version stage ref addresses for a write:
+// ref.VersionStage if set, falling back to ref.Version, and defaulting to
+// AWSCURRENT.
+func targetVersionStage(ref v1.ExternalSecretRef) string {
+	if ref.VersionStage != "" {
+		return ref.VersionStage
+	}
+	if ref.Version != "" {
+		return ref.Version
+	}
+	return awsCurrentStage
+}
+
+// putSecretValue adds payload to an existing secret as a new version, tagged
+// with the stage requested by ref.Version (or ref.VersionStage if given),
+// defaulting to AWSCURRENT.
+func (s *smSecretStore) putSecretValue(ctx context.Context, ref v1.ExternalSecretRef, payload []byte) error {
+	stage := targetVersionStage(ref)
+
+	putSecretValueInput := &secretsmanager.PutSecretValueInput{
+		SecretId:      &ref.Name,
+		VersionStages: []string{stage},
+	}
+	setPayload(payload, &putSecretValueInput.SecretString, &putSecretValueInput.SecretBinary)
+	_, err := s.client.PutSecretValue(ctx, putSecretValueInput)
+	return err
+}
+
+// setPayload assigns payload to secretString when it is valid UTF-8 text, and
+// to secretBinary otherwise, matching how AWS Secrets Manager stores values.
+func setPayload(payload []byte, secretString **string, secretBinary *[]byte) {
+	if utf8.Valid(payload) {
+		v := string(payload)
+		*secretString = &v
+		return
+	}
+	*secretBinary = payload
+}
+
+// currentSecretString returns the current value of a GetSecretValue response as
+// a string, regardless of whether it was stored as SecretString or SecretBinary.
+func currentSecretString(out *secretsmanager.GetSecretValueOutput) string {
+	if out.SecretString != nil {
+		return *out.SecretString
+	}
+	if out.SecretBinary != nil {
+		return string(out.SecretBinary)
+	}
+	return ""
+}
+
+// secretValueEqual reports whether out already holds payload, so SetSecret can
+// skip writing an unchanged value.
+func secretValueEqual(out *secretsmanager.GetSecretValueOutput, payload []byte) bool {
+	if out.SecretString != nil {
+		return *out.SecretString == string(payload)
+	}
+	if out.SecretBinary != nil {
+		return bytes.Equal(out.SecretBinary, payload)
+	}
+	return len(payload) == 0
 }
 
 // buildGetSecretValueInput constructs target GetSecretValueInput request with specific external secret ref.
 func (s *smSecretStore) buildGetSecretValueInput(ref v1.ExternalSecretRef) *secretsmanager.GetSecretValueInput {
-	version := "AWSCURRENT"
+	version := awsCurrentStage
 	if ref.Version != "" {
 		version = ref.Version
 	}
@@ -0,0 +1,147 @@
+package secretsmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// fakeClient is an in-memory stand-in for Client, keyed by secret name. Stage
+// requests beyond the plain AWSCURRENT value tracked in secrets are served
+// from stagedSecrets, keyed by name then stage.
+type fakeClient struct {
+	secrets       map[string]*secretsmanager.GetSecretValueOutput
+	stagedSecrets map[string]map[string]*secretsmanager.GetSecretValueOutput
+
+	createCalls []*secretsmanager.CreateSecretInput
+	putCalls    []*secretsmanager.PutSecretValueInput
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		secrets:       map[string]*secretsmanager.GetSecretValueOutput{},
+		stagedSecrets: map[string]map[string]*secretsmanager.GetSecretValueOutput{},
+	}
+}
+
+func (f *fakeClient) GetSecretValue(_ context.Context, in *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if in.VersionStage != nil {
+		out, ok := f.stagedSecrets[*in.SecretId][*in.VersionStage]
+		if !ok {
+			return nil, &types.ResourceNotFoundException{}
+		}
+		return out, nil
+	}
+	out, ok := f.secrets[*in.SecretId]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{}
+	}
+	return out, nil
+}
+
+func (f *fakeClient) CreateSecret(_ context.Context, in *secretsmanager.CreateSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	f.createCalls = append(f.createCalls, in)
+	f.secrets[*in.Name] = &secretsmanager.GetSecretValueOutput{SecretString: in.SecretString, SecretBinary: in.SecretBinary}
+	return &secretsmanager.CreateSecretOutput{Name: in.Name}, nil
+}
+
+func (f *fakeClient) PutSecretValue(_ context.Context, in *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	f.putCalls = append(f.putCalls, in)
+	f.secrets[*in.SecretId] = &secretsmanager.GetSecretValueOutput{SecretString: in.SecretString, SecretBinary: in.SecretBinary}
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func TestSetSecret(t *testing.T) {
+	t.Run("creates a secret that does not exist", func(t *testing.T) {
+		client := newFakeClient()
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "new-secret"}, []byte("hunter2"))
+		require.NoError(t, err)
+		require.Len(t, client.createCalls, 1)
+		assert.Equal(t, "hunter2", *client.createCalls[0].SecretString)
+		assert.Empty(t, client.putCalls)
+	})
+
+	t.Run("updates an existing secret with the default stage", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr("old")}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing"}, []byte("new"))
+		require.NoError(t, err)
+		require.Len(t, client.putCalls, 1)
+		assert.Equal(t, "new", *client.putCalls[0].SecretString)
+		assert.Equal(t, []string{awsCurrentStage}, client.putCalls[0].VersionStages)
+	})
+
+	t.Run("merges a single property without touching the rest of the payload", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr(`{"username":"alice","password":"old"}`)}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing", Property: "password"}, []byte("new"))
+		require.NoError(t, err)
+		require.Len(t, client.putCalls, 1)
+		assert.JSONEq(t, `{"username":"alice","password":"new"}`, *client.putCalls[0].SecretString)
+	})
+
+	t.Run("tags the new version with a non-default stage", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr("old")}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing", VersionStage: "AWSPREVIOUS"}, []byte("new"))
+		require.NoError(t, err)
+		require.Len(t, client.putCalls, 1)
+		assert.Equal(t, []string{"AWSPREVIOUS"}, client.putCalls[0].VersionStages)
+	})
+
+	t.Run("is a no-op when the value is unchanged", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr("same")}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing"}, []byte("same"))
+		require.NoError(t, err)
+		assert.Empty(t, client.putCalls)
+		assert.Empty(t, client.createCalls)
+	})
+
+	t.Run("is a no-op when the targeted stage already holds the value, even if AWSCURRENT differs", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr("current-value")}
+		client.stagedSecrets["existing"] = map[string]*secretsmanager.GetSecretValueOutput{
+			"AWSPREVIOUS": {SecretString: strPtr("same")},
+		}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing", VersionStage: "AWSPREVIOUS"}, []byte("same"))
+		require.NoError(t, err)
+		assert.Empty(t, client.putCalls)
+	})
+
+	t.Run("merges a property against the targeted stage's payload, not AWSCURRENT's", func(t *testing.T) {
+		client := newFakeClient()
+		client.secrets["existing"] = &secretsmanager.GetSecretValueOutput{SecretString: strPtr(`{"username":"alice","password":"old-current"}`)}
+		client.stagedSecrets["existing"] = map[string]*secretsmanager.GetSecretValueOutput{
+			"AWSPREVIOUS": {SecretString: strPtr(`{"username":"bob","password":"old-previous"}`)},
+		}
+		store := &smSecretStore{client: client}
+
+		err := store.SetSecret(context.Background(), v1.ExternalSecretRef{Name: "existing", Property: "password", VersionStage: "AWSPREVIOUS"}, []byte("new"))
+		require.NoError(t, err)
+		require.Len(t, client.putCalls, 1)
+		assert.JSONEq(t, `{"username":"bob","password":"new"}`, *client.putCalls[0].SecretString)
+	})
+}
+
+func strPtr(s string) *string {
+	return &s
+}
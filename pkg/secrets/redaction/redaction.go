@@ -0,0 +1,199 @@
+// Package redaction scrubs resolved secret values out of Kusion's own output
+// streams (logs, plan diffs, apply output, KCL runtime stdout/stderr) so a
+// secret that was fetched through the secrets package never reaches a
+// terminal or log file in the clear.
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/secrets"
+)
+
+// minSecretLen is the shortest value a Redactor will track. Shorter values
+// match too much incidental output to be worth redacting, so Register
+// silently ignores them rather than producing pathological false positives.
+const minSecretLen = 4
+
+// Redactor records secret values seen during a run and scrubs them out of
+// arbitrary output streams. It is safe for concurrent use.
+type Redactor struct {
+	mu     sync.RWMutex
+	labels map[string]string // secret value -> replacement label
+	maxLen int
+}
+
+// NewRedactingStore wraps inner so every value GetSecret returns is recorded
+// with the returned Redactor under its ref.Name, ready to be scrubbed from
+// later output via Redactor.Wrap.
+func NewRedactingStore(inner secrets.SecretStore) (secrets.SecretStore, *Redactor) {
+	r := &Redactor{labels: map[string]string{}}
+	return &redactingStore{inner: inner, redactor: r}, r
+}
+
+type redactingStore struct {
+	inner    secrets.SecretStore
+	redactor *Redactor
+}
+
+func (s *redactingStore) GetSecret(ctx context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	value, err := s.inner.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	s.redactor.Register(value, ref.Name)
+	return value, nil
+}
+
+func (s *redactingStore) SetSecret(ctx context.Context, ref v1.ExternalSecretRef, secretValue []byte) error {
+	return s.inner.SetSecret(ctx, ref, secretValue)
+}
+
+// Register records value under label so any later output containing it is
+// replaced with "[REDACTED:label]". Values shorter than minSecretLen are
+// ignored.
+func (r *Redactor) Register(value []byte, label string) {
+	if len(value) < minSecretLen {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.labels[string(value)] = label
+	if len(value) > r.maxLen {
+		r.maxLen = len(value)
+	}
+}
+
+// Wrap returns a writer that streams writes through to w with every
+// registered secret value replaced by "[REDACTED:<label>]". It holds back up
+// to the longest registered secret's length so a secret split across two
+// Write calls is still caught.
+func (r *Redactor) Wrap(w io.Writer) io.Writer {
+	return &redactWriter{redactor: r, out: w}
+}
+
+type match struct {
+	start, end int
+	label      string
+}
+
+// findMatches returns the non-overlapping occurrences of registered secrets
+// in buf, scanning left to right and preferring the longest match at each
+// position so that one secret being a substring of another is resolved
+// deterministically.
+func (r *Redactor) findMatches(buf []byte) []match {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []match
+	for i := 0; i < len(buf); {
+		bestLen := 0
+		bestLabel := ""
+		for value, label := range r.labels {
+			if len(value) <= bestLen || i+len(value) > len(buf) {
+				continue
+			}
+			if bytes.Equal(buf[i:i+len(value)], []byte(value)) {
+				bestLen = len(value)
+				bestLabel = label
+			}
+		}
+		if bestLen == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, match{start: i, end: i + bestLen, label: bestLabel})
+		i += bestLen
+	}
+	return matches
+}
+
+// holdBackLen returns how many trailing bytes of a write must be withheld so
+// that a secret starting there could still be completed by the next write.
+func (r *Redactor) holdBackLen() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.maxLen == 0 {
+		return 0
+	}
+	return r.maxLen - 1
+}
+
+// redactWriter is the io.Writer returned by Redactor.Wrap. It is not safe for
+// concurrent use by multiple goroutines, same as any other io.Writer.
+type redactWriter struct {
+	redactor *Redactor
+	out      io.Writer
+	carry    []byte
+}
+
+func (rw *redactWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	buf := append(rw.carry, p...)
+	if err := rw.emit(buf, rw.redactor.holdBackLen()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Flush writes out any bytes still buffered behind a potential, as-yet
+// incomplete secret match, without waiting for more input. Call it once no
+// further data is expected (e.g. the underlying stream is closing) so the
+// last few bytes of output aren't lost.
+func (rw *redactWriter) Flush() error {
+	return rw.emit(rw.carry, 0)
+}
+
+// emit replaces every complete secret match in buf and writes the result to
+// out, holding back the last holdBack bytes as carry for the next call. A
+// match that straddles that boundary is emitted at once if it is as long as
+// the longest registered secret, since nothing can extend it; a shorter one is
+// held back whole, since a longer secret it prefixes may still be completed by
+// the next write.
+func (rw *redactWriter) emit(buf []byte, holdBack int) error {
+	matches := rw.redactor.findMatches(buf)
+	cut := len(buf) - holdBack
+	if cut < 0 {
+		cut = 0
+	}
+	for _, m := range matches {
+		if m.start < cut && m.end > cut {
+			if m.end-m.start > holdBack {
+				cut = m.end
+			} else {
+				cut = m.start
+			}
+			break
+		}
+	}
+
+	out := replaceMatches(buf, matches, cut)
+	rw.carry = append([]byte(nil), buf[cut:]...)
+
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := rw.out.Write(out)
+	return err
+}
+
+// replaceMatches renders buf[:cut] with every match fully contained in that
+// range replaced by its "[REDACTED:label]" placeholder.
+func replaceMatches(buf []byte, matches []match, cut int) []byte {
+	var out bytes.Buffer
+	pos := 0
+	for _, m := range matches {
+		if m.end > cut {
+			break
+		}
+		out.Write(buf[pos:m.start])
+		out.WriteString("[REDACTED:" + m.label + "]")
+		pos = m.end
+	}
+	out.Write(buf[pos:cut])
+	return out.Bytes()
+}
@@ -0,0 +1,117 @@
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+type fakeStore struct {
+	values map[string][]byte
+}
+
+func (s *fakeStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	return s.values[ref.Name], nil
+}
+
+func (s *fakeStore) SetSecret(_ context.Context, _ v1.ExternalSecretRef, _ []byte) error {
+	return nil
+}
+
+func TestNewRedactingStoreRecordsValues(t *testing.T) {
+	inner := &fakeStore{values: map[string][]byte{"db-password": []byte("hunter2pass")}}
+	store, redactor := NewRedactingStore(inner)
+
+	_, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{Name: "db-password"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	w := redactor.Wrap(&out)
+	fmt.Fprintf(w, "connecting with hunter2pass now")
+	flush(t, w)
+
+	assert.Equal(t, "connecting with [REDACTED:db-password] now", out.String())
+}
+
+func TestWrapIgnoresShortValues(t *testing.T) {
+	r := &Redactor{labels: map[string]string{}}
+	r.Register([]byte("ab"), "too-short")
+
+	var out bytes.Buffer
+	w := r.Wrap(&out)
+	fmt.Fprint(w, "value is ab here")
+	flush(t, w)
+
+	assert.Equal(t, "value is ab here", out.String())
+}
+
+func TestWrapHandlesSecretSplitAcrossWrites(t *testing.T) {
+	r := &Redactor{labels: map[string]string{}}
+	r.Register([]byte("supersecretvalue"), "api-key")
+
+	var out bytes.Buffer
+	w := r.Wrap(&out)
+
+	secret := "supersecretvalue"
+	for i := 0; i < len(secret); i++ {
+		_, err := w.Write([]byte{secret[i]})
+		require.NoError(t, err)
+	}
+	fmt.Fprint(w, " and the rest")
+	flush(t, w)
+
+	assert.Equal(t, "[REDACTED:api-key] and the rest", out.String())
+}
+
+func TestWrapHandlesOverlappingSecrets(t *testing.T) {
+	r := &Redactor{labels: map[string]string{}}
+	r.Register([]byte("token"), "short-token")
+	r.Register([]byte("tokenvalue"), "long-token")
+
+	var out bytes.Buffer
+	w := r.Wrap(&out)
+	fmt.Fprint(w, "using tokenvalue here")
+	flush(t, w)
+
+	assert.Equal(t, "using [REDACTED:long-token] here", out.String())
+}
+
+func TestRedactorConcurrentUse(t *testing.T) {
+	r := &Redactor{labels: map[string]string{}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			label := fmt.Sprintf("secret-%d", i)
+			value := []byte(fmt.Sprintf("value-of-secret-number-%d", i))
+			r.Register(value, label)
+
+			var out bytes.Buffer
+			w := r.Wrap(&out)
+			fmt.Fprintf(w, "payload contains %s inline", value)
+			flush(t, w)
+			assert.Contains(t, out.String(), fmt.Sprintf("[REDACTED:%s]", label))
+		}(i)
+	}
+	wg.Wait()
+}
+
+// flush drains any bytes a redactWriter is still holding back, waiting on a
+// potential secret match that will never arrive because the test is done
+// writing.
+func flush(t *testing.T, w io.Writer) {
+	t.Helper()
+	f, ok := w.(interface{ Flush() error })
+	require.True(t, ok, "Wrap should return a flushable writer")
+	require.NoError(t, f.Flush())
+}
@@ -0,0 +1,145 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// recordingProvider is a ConfigurableProvider whose NewSecretStore records the
+// spec it was constructed with, so tests can assert per-instance overrides
+// were threaded through correctly.
+type recordingProvider struct{}
+
+type recordingStore struct {
+	spec *v1.SecretStore
+}
+
+func (s *recordingStore) GetSecret(_ context.Context, _ v1.ExternalSecretRef) ([]byte, error) {
+	return []byte(s.spec.Provider.AWS.Region), nil
+}
+
+func (s *recordingStore) SetSecret(_ context.Context, _ v1.ExternalSecretRef, _ []byte) error {
+	return nil
+}
+
+func (p *recordingProvider) NewSecretStore(spec *v1.SecretStore) (SecretStore, error) {
+	return &recordingStore{spec: spec}, nil
+}
+
+func (p *recordingProvider) DecodeSpec(raw []byte) (*v1.SecretStore, error) {
+	var cfg struct {
+		Region string `json:"region"`
+	}
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, err
+	}
+	return &v1.SecretStore{Provider: &v1.ProviderSpec{AWS: &v1.AWSProvider{Region: cfg.Region}}}, nil
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	providers["recording"] = &recordingProvider{}
+	defer delete(providers, "recording")
+
+	t.Run("rejects duplicate ids", func(t *testing.T) {
+		_, err := NewRegistryFromConfig([]ProviderConfig{
+			{ID: "dup", Type: "recording", Config: []byte(`{"region":"us-east-1"}`)},
+			{ID: "dup", Type: "recording", Config: []byte(`{"region":"us-west-2"}`)},
+		})
+		assert.ErrorContains(t, err, "duplicate secret store id")
+	})
+
+	t.Run("rejects unknown provider types", func(t *testing.T) {
+		_, err := NewRegistryFromConfig([]ProviderConfig{
+			{ID: "a", Type: "does-not-exist", Config: []byte(`{}`)},
+		})
+		assert.ErrorContains(t, err, "unknown secret store provider type")
+	})
+
+	t.Run("builds one store per instance with its own overrides", func(t *testing.T) {
+		reg, err := NewRegistryFromConfig([]ProviderConfig{
+			{ID: "prod-ssm", Type: "recording", Config: []byte(`{"region":"us-east-1"}`)},
+			{ID: "staging-ssm", Type: "recording", Config: []byte(`{"region":"us-west-2"}`)},
+		})
+		require.NoError(t, err)
+
+		prod, ok := reg.Get("prod-ssm")
+		require.True(t, ok)
+		val, err := prod.GetSecret(context.Background(), v1.ExternalSecretRef{})
+		require.NoError(t, err)
+		assert.Equal(t, "us-east-1", string(val))
+
+		staging, ok := reg.Get("staging-ssm")
+		require.True(t, ok)
+		val, err = staging.GetSecret(context.Background(), v1.ExternalSecretRef{})
+		require.NoError(t, err)
+		assert.Equal(t, "us-west-2", string(val))
+
+		_, ok = reg.Get("unknown")
+		assert.False(t, ok)
+	})
+
+	t.Run("round-trips a YAML config document", func(t *testing.T) {
+		doc := []byte(`
+- id: prod-ssm
+  type: recording
+  config:
+    region: us-east-1
+`)
+		var configs []ProviderConfig
+		require.NoError(t, yaml.Unmarshal(doc, &configs))
+
+		reg, err := NewRegistryFromConfig(configs)
+		require.NoError(t, err)
+
+		store, ok := reg.Get("prod-ssm")
+		require.True(t, ok)
+		val, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{})
+		require.NoError(t, err)
+		assert.Equal(t, "us-east-1", string(val))
+	})
+}
+
+func TestRegistryResolve(t *testing.T) {
+	// providerName maps an AWS spec to "aws", so stand the fake provider in
+	// under that key to exercise Resolve without a real AWS dependency.
+	providers["aws"] = &recordingProvider{}
+	defer delete(providers, "aws")
+
+	reg := NewDefaultRegistry()
+	spec := &v1.SecretStore{Provider: &v1.ProviderSpec{AWS: &v1.AWSProvider{Region: "eu-central-1"}}}
+
+	store, err := reg.Resolve(spec)
+	require.NoError(t, err)
+	val, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central-1", string(val))
+
+	// Resolve caches the store in the registry under the provider type's name.
+	again, ok := reg.Get("aws")
+	require.True(t, ok)
+	assert.Same(t, store, again)
+}
+
+func TestRegistryGetForRef(t *testing.T) {
+	providers["recording"] = &recordingProvider{}
+	defer delete(providers, "recording")
+	reg, err := NewRegistryFromConfig([]ProviderConfig{
+		{ID: "prod-ssm", Type: "recording", Config: []byte(`{"region":"us-east-1"}`)},
+	})
+	require.NoError(t, err)
+
+	store, ok := reg.GetForRef(v1.ExternalSecretRef{StoreID: "prod-ssm", Name: "db"})
+	require.True(t, ok)
+	val, err := store.GetSecret(context.Background(), v1.ExternalSecretRef{StoreID: "prod-ssm", Name: "db"})
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", string(val))
+
+	_, ok = reg.GetForRef(v1.ExternalSecretRef{StoreID: "unknown"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,293 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// refPattern matches an inline secret reference of the form
+// ${secret-ref://<store-id>/<name>[#property][@version]}.
+var refPattern = regexp.MustCompile(`\$\{secret-ref://[^}]*\}`)
+
+// escapeMarker stands in for an escaped "$${" while refPattern runs, so an
+// escaped reference is never mistaken for a real one.
+const escapeMarker = "\x00kusion-secret-ref-escape\x00"
+
+// Resolver walks a value looking for inline ${secret-ref://...} placeholders
+// and replaces them with the referenced secret's value, fetched through a
+// Registry.
+type Resolver struct {
+	reg    *Registry
+	strict bool
+}
+
+// NewResolver returns a Resolver that looks up secrets in reg. By default a
+// reference to a secret that does not exist is left untouched in the output;
+// call Strict to fail instead.
+func NewResolver(reg *Registry) *Resolver {
+	return &Resolver{reg: reg}
+}
+
+// Strict makes Resolve return an *UnresolvedReferenceError instead of leaving
+// the placeholder in place when a referenced secret is missing. It returns the
+// Resolver so it can be chained onto NewResolver.
+func (r *Resolver) Strict(strict bool) *Resolver {
+	r.strict = strict
+	return r
+}
+
+// UnresolvedReferenceError reports a ${secret-ref://...} placeholder that
+// Resolve could not fill in, identified by its location in v.
+type UnresolvedReferenceError struct {
+	// Path is the JSON-path-style location of the reference within the value
+	// passed to Resolve, e.g. "$.spec.containers[0].env.password".
+	Path string
+	// Ref is the raw, unresolved placeholder text.
+	Ref string
+}
+
+func (e *UnresolvedReferenceError) Error() string {
+	return fmt.Sprintf("unresolved secret reference %s at %s", e.Ref, e.Path)
+}
+
+// secretRefCacheKey identifies a single GetSecret call so Resolve can
+// coalesce repeated references to the same secret within one invocation.
+type secretRefCacheKey struct {
+	storeID  string
+	name     string
+	property string
+	version  string
+}
+
+// Resolve returns a copy of v with every ${secret-ref://...} placeholder found
+// in its strings replaced by the referenced secret's value. Maps, slices,
+// arrays, structs and pointers are traversed depth-first; all other values are
+// returned unchanged. Identical references are only fetched once per Resolve
+// call.
+func (r *Resolver) Resolve(ctx context.Context, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	cache := map[secretRefCacheKey][]byte{}
+	out, err := r.resolveValue(ctx, "$", reflect.ValueOf(v), cache)
+	if err != nil {
+		return nil, err
+	}
+	return out.Interface(), nil
+}
+
+func (r *Resolver) resolveValue(ctx context.Context, path string, v reflect.Value, cache map[secretRefCacheKey][]byte) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.String:
+		resolved, err := r.resolveString(ctx, path, v.String(), cache)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(resolved)
+		return out, nil
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil
+		}
+		elem, err := r.resolveValue(ctx, path, v.Elem(), cache)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(elem)
+		return out, nil
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		elem, err := r.resolveValue(ctx, path, v.Elem(), cache)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(elem)
+		return out, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			childPath := fmt.Sprintf("%s.%v", path, iter.Key().Interface())
+			val, err := r.resolveValue(ctx, childPath, iter.Value(), cache)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.SetMapIndex(iter.Key(), val)
+		}
+		return out, nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v, nil
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			val, err := r.resolveValue(ctx, fmt.Sprintf("%s[%d]", path, i), v.Index(i), cache)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(val)
+		}
+		return out, nil
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			val, err := r.resolveValue(ctx, fmt.Sprintf("%s[%d]", path, i), v.Index(i), cache)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(val)
+		}
+		return out, nil
+
+	case reflect.Struct:
+		// Start from a full copy so unexported fields, which cannot be read
+		// back through reflect, survive untouched.
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			val, err := r.resolveValue(ctx, path+"."+field.Name, v.Field(i), cache)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Field(i).Set(val)
+		}
+		return out, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// resolveString replaces every ${secret-ref://...} placeholder in s, honoring
+// $${...} as an escape for a literal ${...}.
+func (r *Resolver) resolveString(ctx context.Context, path, s string, cache map[secretRefCacheKey][]byte) (string, error) {
+	escaped := strings.Contains(s, "$${")
+	if escaped {
+		s = strings.ReplaceAll(s, "$${", escapeMarker+"{")
+	}
+
+	var resolveErr error
+	result := refPattern.ReplaceAllStringFunc(s, func(raw string) string {
+		if resolveErr != nil {
+			return raw
+		}
+		ref, err := parseSecretRef(raw)
+		if err != nil {
+			resolveErr = fmt.Errorf("%s: %w", path, err)
+			return raw
+		}
+		value, found, err := r.lookup(ctx, ref, cache)
+		if err != nil {
+			resolveErr = fmt.Errorf("%s: %w", path, err)
+			return raw
+		}
+		if !found {
+			if r.strict {
+				resolveErr = &UnresolvedReferenceError{Path: path, Ref: raw}
+			}
+			return raw
+		}
+		return encodeSecretValue(value)
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	if escaped {
+		result = strings.ReplaceAll(result, escapeMarker+"{", "${")
+	}
+	return result, nil
+}
+
+// secretRef is a parsed ${secret-ref://<store-id>/<name>[#property][@version]}
+// placeholder.
+type secretRef struct {
+	storeID  string
+	name     string
+	property string
+	version  string
+}
+
+func parseSecretRef(raw string) (secretRef, error) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(raw, "${secret-ref://"), "}")
+	storeID, rest, ok := strings.Cut(inner, "/")
+	if !ok || storeID == "" || rest == "" {
+		return secretRef{}, fmt.Errorf("malformed secret reference %s: expected ${secret-ref://<store-id>/<name>[#property][@version]}", raw)
+	}
+
+	ref := secretRef{storeID: storeID}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		ref.version = rest[at+1:]
+		rest = rest[:at]
+	}
+	if hash := strings.IndexByte(rest, '#'); hash >= 0 {
+		ref.property = rest[hash+1:]
+		rest = rest[:hash]
+	}
+	ref.name = rest
+	if ref.name == "" {
+		return secretRef{}, fmt.Errorf("malformed secret reference %s: missing secret name", raw)
+	}
+	return ref, nil
+}
+
+// lookup resolves ref, reusing a prior fetch of the same (store, name,
+// property, version) within this Resolve call. found is false when the store
+// reported the secret does not exist; an unknown store is always an error.
+func (r *Resolver) lookup(ctx context.Context, ref secretRef, cache map[secretRefCacheKey][]byte) (value []byte, found bool, err error) {
+	key := secretRefCacheKey{storeID: ref.storeID, name: ref.name, property: ref.property, version: ref.version}
+	if cached, ok := cache[key]; ok {
+		return cached, cached != nil, nil
+	}
+
+	secretRef := v1.ExternalSecretRef{StoreID: ref.storeID, Name: ref.name, Property: ref.property, Version: ref.version}
+	store, ok := r.reg.GetForRef(secretRef)
+	if !ok {
+		return nil, false, fmt.Errorf("no secret store registered with id %q", ref.storeID)
+	}
+
+	value, err = store.GetSecret(ctx, secretRef)
+	if err != nil {
+		return nil, false, fmt.Errorf("get secret %s/%s: %w", ref.storeID, ref.name, err)
+	}
+
+	cache[key] = value
+	if value == nil {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// encodeSecretValue renders a secret value for substitution into a string:
+// valid UTF-8 text is used as-is, anything else is base64-encoded so it can
+// be embedded safely.
+func encodeSecretValue(value []byte) string {
+	if utf8.Valid(value) {
+		return string(value)
+	}
+	return base64.StdEncoding.EncodeToString(value)
+}
@@ -0,0 +1,172 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// countingStore serves canned values by ref.Name and counts how many times
+// GetSecret was called, so tests can assert on batching.
+type countingStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	calls  int
+}
+
+func (s *countingStore) GetSecret(_ context.Context, ref v1.ExternalSecretRef) ([]byte, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return s.values[ref.Name], nil
+}
+
+func (s *countingStore) SetSecret(_ context.Context, _ v1.ExternalSecretRef, _ []byte) error {
+	return nil
+}
+
+func testRegistry(stores map[string]SecretStore) *Registry {
+	return &Registry{stores: stores}
+}
+
+func TestResolverResolvesNestedMapsAndSlices(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{"db-password": []byte("hunter2")}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	input := map[string]any{
+		"env": []any{
+			map[string]any{"name": "PASSWORD", "value": "${secret-ref://prod/db-password}"},
+			map[string]any{"name": "STATIC", "value": "unchanged"},
+		},
+	}
+
+	out, err := r.Resolve(context.Background(), input)
+	require.NoError(t, err)
+
+	env := out.(map[string]any)["env"].([]any)
+	assert.Equal(t, "hunter2", env[0].(map[string]any)["value"])
+	assert.Equal(t, "unchanged", env[1].(map[string]any)["value"])
+}
+
+func TestResolverResolvesStructs(t *testing.T) {
+	type EnvVar struct {
+		Name  string
+		Value string
+	}
+	type Workload struct {
+		Env []EnvVar
+	}
+
+	store := &countingStore{values: map[string][]byte{"api-key": []byte("topsecret")}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	input := Workload{Env: []EnvVar{{Name: "API_KEY", Value: "${secret-ref://prod/api-key}"}}}
+
+	out, err := r.Resolve(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, "topsecret", out.(Workload).Env[0].Value)
+}
+
+func TestResolverSupportsPropertyAndVersion(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{"creds": []byte("value-for-creds")}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	out, err := r.Resolve(context.Background(), "${secret-ref://prod/creds#password@v2}")
+	require.NoError(t, err)
+	assert.Equal(t, "value-for-creds", out)
+}
+
+func TestResolverEncodesBinarySecretsAsBase64(t *testing.T) {
+	binary := []byte{0xff, 0x00, 0xfe, 0x01}
+	store := &countingStore{values: map[string][]byte{"blob": binary}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	out, err := r.Resolve(context.Background(), "${secret-ref://prod/blob}")
+	require.NoError(t, err)
+	assert.Equal(t, "/wD+AQ==", out)
+}
+
+func TestResolverEscapesLiteralDollarBrace(t *testing.T) {
+	reg := testRegistry(map[string]SecretStore{})
+	r := NewResolver(reg)
+
+	out, err := r.Resolve(context.Background(), "literal: $${secret-ref://prod/name}")
+	require.NoError(t, err)
+	assert.Equal(t, "literal: ${secret-ref://prod/name}", out)
+}
+
+func TestResolverLeavesUnresolvedPlaceholderByDefault(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	out, err := r.Resolve(context.Background(), "${secret-ref://prod/missing}")
+	require.NoError(t, err)
+	assert.Equal(t, "${secret-ref://prod/missing}", out)
+}
+
+func TestResolverStrictModeFailsOnMissingSecret(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg).Strict(true)
+
+	_, err := r.Resolve(context.Background(), "${secret-ref://prod/missing}")
+	require.Error(t, err)
+	var unresolved *UnresolvedReferenceError
+	require.ErrorAs(t, err, &unresolved)
+	assert.Equal(t, "$", unresolved.Path)
+}
+
+func TestResolverErrorsOnUnknownStore(t *testing.T) {
+	reg := testRegistry(map[string]SecretStore{})
+	r := NewResolver(reg)
+
+	_, err := r.Resolve(context.Background(), "${secret-ref://missing-store/name}")
+	assert.ErrorContains(t, err, `no secret store registered with id "missing-store"`)
+}
+
+func TestResolverBatchesRepeatedReferences(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{"shared": []byte("value")}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	input := []any{
+		"${secret-ref://prod/shared}",
+		"${secret-ref://prod/shared}",
+		"${secret-ref://prod/shared}",
+	}
+
+	out, err := r.Resolve(context.Background(), input)
+	require.NoError(t, err)
+	for _, v := range out.([]any) {
+		assert.Equal(t, "value", v)
+	}
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestResolverConcurrentResolution(t *testing.T) {
+	store := &countingStore{values: map[string][]byte{"shared": []byte("value")}}
+	reg := testRegistry(map[string]SecretStore{"prod": store})
+	r := NewResolver(reg)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := r.Resolve(context.Background(), "${secret-ref://prod/shared}")
+			assert.NoError(t, err)
+			assert.Equal(t, "value", out)
+		}()
+	}
+	wg.Wait()
+}